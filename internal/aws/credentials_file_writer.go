@@ -0,0 +1,136 @@
+/*
+ * Copyright (c) 2022-Present, Okta, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package aws
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/gofrs/flock"
+	"gopkg.in/ini.v1"
+)
+
+// configProfileSectionName returns the [profile <name>] section header
+// AWS config files use for named profiles, matching the "default" exception
+// the AWS CLI itself applies.
+func configProfileSectionName(profile string) string {
+	if profile == "default" {
+		return "default"
+	}
+	return "profile " + profile
+}
+
+// CredentialsFileWriter merges credentials and credential_process stanzas
+// into the AWS CLI's ~/.aws/credentials and ~/.aws/config files without
+// clobbering unrelated profiles. Writes are atomic (tempfile+rename) and
+// serialized with a file lock so concurrent invocations from multiple
+// shells can't corrupt the ini file.
+type CredentialsFileWriter struct {
+	// CredentialsPath is the path to the AWS credentials file, typically
+	// ~/.aws/credentials.
+	CredentialsPath string
+	// ConfigPath is the path to the AWS config file, typically
+	// ~/.aws/config.
+	ConfigPath string
+}
+
+// WriteCredentials merges cred into the [profile] section named by
+// cred.Profile() in CredentialsPath, leaving every other section untouched.
+func (w *CredentialsFileWriter) WriteCredentials(cred *CredsFileCredential) error {
+	return w.mergeSection(w.CredentialsPath, cred.Profile(), func(s *ini.Section) error {
+		return s.ReflectFrom(cred)
+	})
+}
+
+// WriteProcessProfile merges a `credential_process = command` stanza into
+// the [profile <profile>] section of ConfigPath, so the AWS SDK invokes
+// okta-aws-cli itself instead of reading static keys.
+func (w *CredentialsFileWriter) WriteProcessProfile(profile, command string) error {
+	return w.mergeSection(w.ConfigPath, configProfileSectionName(profile), func(s *ini.Section) error {
+		s.Key("credential_process").SetValue(command)
+		return nil
+	})
+}
+
+// mergeSection loads path (creating an empty file if it doesn't exist yet),
+// applies set to the named section, and atomically writes the result back,
+// holding an exclusive file lock for the duration so concurrent writers
+// serialize instead of racing.
+func (w *CredentialsFileWriter) mergeSection(path, section string, set func(*ini.Section) error) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("creating %s directory: %w", path, err)
+	}
+
+	lock := flock.New(path + ".lock")
+	if err := lock.Lock(); err != nil {
+		return fmt.Errorf("locking %s: %w", path, err)
+	}
+	defer lock.Unlock()
+
+	cfg, err := w.load(path)
+	if err != nil {
+		return err
+	}
+
+	sec, err := cfg.NewSection(section)
+	if err != nil {
+		return fmt.Errorf("opening section %q in %s: %w", section, path, err)
+	}
+	if err := set(sec); err != nil {
+		return fmt.Errorf("updating section %q in %s: %w", section, path, err)
+	}
+
+	return w.atomicSave(cfg, path)
+}
+
+// load reads path into an *ini.File, treating a missing file as empty so
+// the first write to ~/.aws/credentials or ~/.aws/config just creates it.
+func (w *CredentialsFileWriter) load(path string) (*ini.File, error) {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return ini.Empty(), nil
+	}
+
+	cfg, err := ini.Load(path)
+	if err != nil {
+		return nil, fmt.Errorf("loading %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// atomicSave writes cfg to a tempfile in the same directory as path and
+// renames it into place, so readers never observe a partially written file.
+func (w *CredentialsFileWriter) atomicSave(cfg *ini.File, path string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("creating tempfile for %s: %w", path, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := cfg.WriteTo(tmp); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("closing tempfile for %s: %w", path, err)
+	}
+	if err := os.Chmod(tmp.Name(), 0600); err != nil {
+		return fmt.Errorf("setting permissions on %s: %w", path, err)
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
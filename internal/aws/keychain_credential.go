@@ -0,0 +1,277 @@
+/*
+ * Copyright (c) 2022-Present, Okta, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package aws
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/zalando/go-keyring"
+)
+
+// keychainServicePrefix namespaces this tool's entries in the OS secure
+// store so they don't collide with unrelated secrets.
+const keychainServicePrefix = "okta-aws-cli"
+
+// reservedIndexProfile is the profile-index's own name within the
+// keychainServicePrefix namespace. It's rejected as a KeychainCredential
+// profile name because KeychainCredential.service() would otherwise
+// produce the exact same service string as keychainIndexService, letting a
+// credential save silently overwrite the profile index (and vice versa).
+const reservedIndexProfile = "__profiles__"
+
+// keychainIndexService is a fixed, well-known service name holding the list
+// of profiles stored under keychainServicePrefix. go-keyring (like the
+// underlying OS secure stores) has no enumeration API, so `store list` has
+// nothing to iterate without this companion index.
+const keychainIndexService = keychainServicePrefix + ":" + reservedIndexProfile
+
+// ErrReservedProfile is returned by KeychainCredential when Profile is the
+// name reserved for the internal profile index.
+var ErrReservedProfile = errors.New("profile name is reserved for internal use")
+
+// ErrSecureStoreNotFound is returned by SecureStore.Get when no entry
+// exists for the given service, distinguishing "absent" from transient
+// backend failures so callers like loadIndex don't mistake one for the
+// other.
+var ErrSecureStoreNotFound = errors.New("secure store: entry not found")
+
+// SecureStore persists and retrieves a single secret blob under a service
+// name, backed by the OS secure store (macOS Keychain, Windows Credential
+// Manager, libsecret/KWallet on Linux). It exists as an interface so
+// KeychainCredential can be tested against an in-memory fake. Get must
+// return ErrSecureStoreNotFound (wrapped or not) when the entry is absent.
+type SecureStore interface {
+	Set(service, value string) error
+	Get(service string) (string, error)
+	Delete(service string) error
+}
+
+// keyringStore is the default SecureStore, backed by go-keyring.
+type keyringStore struct{}
+
+func (keyringStore) Set(service, value string) error {
+	return keyring.Set(service, keychainServicePrefix, value)
+}
+
+func (keyringStore) Get(service string) (string, error) {
+	v, err := keyring.Get(service, keychainServicePrefix)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return "", ErrSecureStoreNotFound
+	}
+	return v, err
+}
+
+func (keyringStore) Delete(service string) error {
+	return keyring.Delete(service, keychainServicePrefix)
+}
+
+// DefaultSecureStore is the SecureStore used by KeychainCredential when
+// Store is left unset.
+var DefaultSecureStore SecureStore = keyringStore{}
+
+// KeychainCredential representation of an AWS credential persisted to the
+// OS secure store under the service name "okta-aws-cli:<profile>", so
+// long-lived IAM user keys can be managed by the `store` subcommand
+// alongside the ephemeral STS credentials this tool mints.
+type KeychainCredential struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	Expiration      *time.Time
+
+	Profile string
+
+	// Store is the backing SecureStore. Defaults to DefaultSecureStore when
+	// nil.
+	Store SecureStore
+}
+
+// IsCredential keychain credential is a credential
+func (k *KeychainCredential) IsCredential() bool { return true }
+
+// keychainEntry is the JSON blob persisted as the secret value.
+type keychainEntry struct {
+	AccessKeyID     string     `json:"accessKeyId"`
+	SecretAccessKey string     `json:"secretAccessKey"`
+	SessionToken    string     `json:"sessionToken,omitempty"`
+	Expiration      *time.Time `json:"expiration,omitempty"`
+}
+
+// service returns the keychain service name this credential is stored
+// under.
+func (k *KeychainCredential) service() string {
+	return fmt.Sprintf("%s:%s", keychainServicePrefix, k.Profile)
+}
+
+func (k *KeychainCredential) store() SecureStore {
+	if k.Store != nil {
+		return k.Store
+	}
+	return DefaultSecureStore
+}
+
+// Save persists k's credential fields to the OS secure store and records
+// k.Profile in the store's profile index so it can later be enumerated by
+// ListProfiles.
+func (k *KeychainCredential) Save() error {
+	if k.Profile == reservedIndexProfile {
+		return ErrReservedProfile
+	}
+
+	entry := keychainEntry{
+		AccessKeyID:     k.AccessKeyID,
+		SecretAccessKey: k.SecretAccessKey,
+		SessionToken:    k.SessionToken,
+		Expiration:      k.Expiration,
+	}
+
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("marshaling keychain entry: %w", err)
+	}
+
+	if err := k.store().Set(k.service(), string(b)); err != nil {
+		return err
+	}
+
+	return addProfileToIndex(k.store(), k.Profile)
+}
+
+// Load populates k's credential fields from the OS secure store. It returns
+// an error if no entry exists for k.Profile.
+func (k *KeychainCredential) Load() error {
+	if k.Profile == reservedIndexProfile {
+		return ErrReservedProfile
+	}
+
+	raw, err := k.store().Get(k.service())
+	if err != nil {
+		return fmt.Errorf("loading keychain entry for profile %q: %w", k.Profile, err)
+	}
+
+	var entry keychainEntry
+	if err := json.Unmarshal([]byte(raw), &entry); err != nil {
+		return fmt.Errorf("unmarshaling keychain entry: %w", err)
+	}
+
+	k.AccessKeyID = entry.AccessKeyID
+	k.SecretAccessKey = entry.SecretAccessKey
+	k.SessionToken = entry.SessionToken
+	k.Expiration = entry.Expiration
+	return nil
+}
+
+// Delete removes k's entry, and its profile index record, from the OS
+// secure store.
+func (k *KeychainCredential) Delete() error {
+	if k.Profile == reservedIndexProfile {
+		return ErrReservedProfile
+	}
+
+	if err := k.store().Delete(k.service()); err != nil {
+		return err
+	}
+	return removeProfileFromIndex(k.store(), k.Profile)
+}
+
+// ListProfiles returns the profile names of every KeychainCredential saved
+// to store, for use by the `store list` command.
+func ListProfiles(store SecureStore) ([]string, error) {
+	if store == nil {
+		store = DefaultSecureStore
+	}
+	return loadIndex(store)
+}
+
+// keychainIndexMu serializes read-modify-write access to the profile index
+// within this process. It does not protect against concurrent writers in
+// separate processes, unlike CredentialsFileWriter's flock-based merges,
+// since the OS secure store APIs expose no cross-process locking primitive.
+var keychainIndexMu sync.Mutex
+
+// loadIndex returns the current profile index, treating a missing index
+// entry as an empty list. Any other error (a transient backend failure,
+// for example) is propagated rather than silently treated as empty, so
+// callers don't overwrite a good index with a partial one.
+func loadIndex(store SecureStore) ([]string, error) {
+	raw, err := store.Get(keychainIndexService)
+	if errors.Is(err, ErrSecureStoreNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading keychain profile index: %w", err)
+	}
+
+	var profiles []string
+	if err := json.Unmarshal([]byte(raw), &profiles); err != nil {
+		return nil, fmt.Errorf("unmarshaling keychain profile index: %w", err)
+	}
+	return profiles, nil
+}
+
+// saveIndex persists profiles as the profile index.
+func saveIndex(store SecureStore, profiles []string) error {
+	b, err := json.Marshal(profiles)
+	if err != nil {
+		return fmt.Errorf("marshaling keychain profile index: %w", err)
+	}
+	return store.Set(keychainIndexService, string(b))
+}
+
+// addProfileToIndex records profile in the index if it isn't already
+// present.
+func addProfileToIndex(store SecureStore, profile string) error {
+	keychainIndexMu.Lock()
+	defer keychainIndexMu.Unlock()
+
+	profiles, err := loadIndex(store)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range profiles {
+		if p == profile {
+			return nil
+		}
+	}
+
+	return saveIndex(store, append(profiles, profile))
+}
+
+// removeProfileFromIndex removes profile from the index, if present.
+func removeProfileFromIndex(store SecureStore, profile string) error {
+	keychainIndexMu.Lock()
+	defer keychainIndexMu.Unlock()
+
+	profiles, err := loadIndex(store)
+	if err != nil {
+		return err
+	}
+
+	out := profiles[:0]
+	for _, p := range profiles {
+		if p != profile {
+			out = append(out, p)
+		}
+	}
+
+	return saveIndex(store, out)
+}
@@ -0,0 +1,102 @@
+/*
+ * Copyright (c) 2022-Present, Okta, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package aws
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// outputFormatVersion is bumped whenever the JSON/YAML shape below changes,
+// so downstream consumers (jq, Ansible, Terraform's `external` data source)
+// can evolve alongside it.
+const outputFormatVersion = 1
+
+// RawFederationDetails carries the original SAML assertion or OIDC claims
+// used to mint a credential, for debugging federated role mappings. It is
+// only populated, and only emitted, when OutputFormat.Raw is set.
+type RawFederationDetails struct {
+	SAMLAssertion string            `json:"saml_assertion,omitempty" yaml:"saml_assertion,omitempty"`
+	OIDCClaims    map[string]string `json:"oidc_claims,omitempty" yaml:"oidc_claims,omitempty"`
+}
+
+// outputFormatDoc is the canonical shape emitted for both JSON and YAML,
+// distinct from ProcessCredential's AWS-mandated shape.
+type outputFormatDoc struct {
+	FormatVersion   int                   `json:"format_version" yaml:"format_version"`
+	AccessKeyID     string                `json:"access_key_id,omitempty" yaml:"access_key_id,omitempty"`
+	SecretAccessKey string                `json:"secret_access_key,omitempty" yaml:"secret_access_key,omitempty"`
+	SessionToken    string                `json:"session_token,omitempty" yaml:"session_token,omitempty"`
+	Expiration      string                `json:"expiration,omitempty" yaml:"expiration,omitempty"`
+	Profile         string                `json:"profile,omitempty" yaml:"profile,omitempty"`
+	Raw             *RawFederationDetails `json:"raw,omitempty" yaml:"raw,omitempty"`
+}
+
+// OutputFormat renders a CredentialContainer as canonical JSON or YAML for
+// piping into external tooling, rather than the AWS-mandated
+// ProcessCredential shape.
+type OutputFormat struct {
+	// Raw additionally includes the original SAML assertion/OIDC claims
+	// used to mint the credential, for debugging federated role mappings.
+	Raw bool
+	// RawDetails is only read when Raw is true.
+	RawDetails RawFederationDetails
+}
+
+// doc builds the canonical document for c, honoring o.Raw.
+func (o *OutputFormat) doc(c *CredentialContainer) outputFormatDoc {
+	var exp string
+	if c.Expiration != nil {
+		exp = c.Expiration.Format(time.RFC3339)
+	}
+
+	doc := outputFormatDoc{
+		FormatVersion:   outputFormatVersion,
+		AccessKeyID:     c.AccessKeyID,
+		SecretAccessKey: c.SecretAccessKey,
+		SessionToken:    c.SessionToken,
+		Expiration:      exp,
+		Profile:         c.Profile,
+	}
+	if o.Raw {
+		doc.Raw = &o.RawDetails
+	}
+	return doc
+}
+
+// JSON renders c as canonical JSON, including a "format_version"
+// discriminator.
+func (o *OutputFormat) JSON(c *CredentialContainer) ([]byte, error) {
+	b, err := json.MarshalIndent(o.doc(c), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshaling credential as json: %w", err)
+	}
+	return b, nil
+}
+
+// YAML renders c as canonical YAML, using RFC3339 for Expiration to match
+// ProcessCredential.MarshalJSON, and omitting empty fields.
+func (o *OutputFormat) YAML(c *CredentialContainer) ([]byte, error) {
+	b, err := yaml.Marshal(o.doc(c))
+	if err != nil {
+		return nil, fmt.Errorf("marshaling credential as yaml: %w", err)
+	}
+	return b, nil
+}
@@ -0,0 +1,126 @@
+/*
+ * Copyright (c) 2022-Present, Okta, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package aws
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// processCacheSchemaVersion is bumped whenever the on-disk cache entry shape
+// changes so future formats (e.g. SSO-style) can coexist.
+const processCacheSchemaVersion = 1
+
+// defaultProcessCacheSkew is how far ahead of Expiration the cache is
+// considered stale, matching the AWS SDK's own refresh window.
+const defaultProcessCacheSkew = 5 * time.Minute
+
+// ProcessCacheKey identifies a cached credential_process result. A cache hit
+// requires every field to match the current invocation.
+type ProcessCacheKey struct {
+	OIDCClientID string
+	IdP          string
+	RoleARN      string
+	Duration     time.Duration
+}
+
+// hash returns the cache filename for k, derived the same way the AWS CLI
+// derives its SSO cache filenames: a hex SHA1 of the key's identity.
+func (k ProcessCacheKey) hash() string {
+	h := sha1.New()
+	fmt.Fprintf(h, "%s|%s|%s|%s", k.OIDCClientID, k.IdP, k.RoleARN, k.Duration)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// processCacheEntry is the on-disk representation of a cached
+// credential_process result.
+type processCacheEntry struct {
+	SchemaVersion int               `json:"schemaVersion"`
+	Key           ProcessCacheKey   `json:"key"`
+	Credential    ProcessCredential `json:"credential"`
+}
+
+// ProcessCredentialCache reads and writes cached credential_process results
+// to disk, keyed by (OIDC client, IdP, role, duration), so that repeated AWS
+// SDK invocations don't each trigger a full Okta login.
+type ProcessCredentialCache struct {
+	// Dir is the directory cache entries are stored under, e.g.
+	// ~/.okta/aws-cli/cache.
+	Dir string
+	// Skew is how far ahead of Expiration a cached credential is treated as
+	// expired. Defaults to defaultProcessCacheSkew when zero.
+	Skew time.Duration
+}
+
+// Get returns the cached credential for key, or ok=false if there is no
+// cache entry, it doesn't match key, or it's within Skew of expiring.
+func (c *ProcessCredentialCache) Get(key ProcessCacheKey) (cred *ProcessCredential, ok bool) {
+	b, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+
+	var entry processCacheEntry
+	if err := json.Unmarshal(b, &entry); err != nil {
+		return nil, false
+	}
+
+	if entry.SchemaVersion != processCacheSchemaVersion || entry.Key != key {
+		return nil, false
+	}
+
+	skew := c.Skew
+	if skew == 0 {
+		skew = defaultProcessCacheSkew
+	}
+	if entry.Credential.Expiration == nil || time.Now().Add(skew).After(*entry.Credential.Expiration) {
+		return nil, false
+	}
+
+	return &entry.Credential, true
+}
+
+// Put writes cred to the cache under key, creating Dir if necessary. The
+// file is created with 0600 permissions since it holds live AWS credentials.
+func (c *ProcessCredentialCache) Put(key ProcessCacheKey, cred *ProcessCredential) error {
+	if err := os.MkdirAll(c.Dir, 0700); err != nil {
+		return fmt.Errorf("creating process credential cache dir: %w", err)
+	}
+
+	entry := processCacheEntry{
+		SchemaVersion: processCacheSchemaVersion,
+		Key:           key,
+		Credential:    *cred,
+	}
+
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("marshaling process credential cache entry: %w", err)
+	}
+
+	return os.WriteFile(c.path(key), b, 0600)
+}
+
+// path returns the on-disk path for key's cache entry.
+func (c *ProcessCredentialCache) path(key ProcessCacheKey) string {
+	return filepath.Join(c.Dir, key.hash()+".json")
+}
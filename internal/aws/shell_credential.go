@@ -0,0 +1,132 @@
+/*
+ * Copyright (c) 2022-Present, Okta, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package aws
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Shell identifies the shell syntax that a ShellCredential should render
+// its export statements in.
+type Shell int
+
+const (
+	// Bash covers bash and any other POSIX sh-compatible shell.
+	Bash Shell = iota
+	// Zsh renders the same POSIX export syntax as Bash.
+	Zsh
+	// Fish renders fish's `set -x` syntax.
+	Fish
+	// PowerShell renders `$env:` assignment syntax.
+	PowerShell
+	// Cmd renders cmd.exe `set` syntax.
+	Cmd
+)
+
+// ShellCredential representation of an AWS credential rendered as shell
+// export statements so it can be eval'd / Invoke-Expression'd directly into
+// the calling shell's environment.
+type ShellCredential struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	Shell           Shell
+}
+
+// IsCredential shell credential is a credential
+func (s *ShellCredential) IsCredential() bool { return true }
+
+// String renders the credential as export statements in the syntax of s.Shell.
+func (s *ShellCredential) String() string {
+	vars := map[string]string{
+		"AWS_ACCESS_KEY_ID":     s.AccessKeyID,
+		"AWS_SECRET_ACCESS_KEY": s.SecretAccessKey,
+	}
+	if s.SessionToken != "" {
+		vars["AWS_SESSION_TOKEN"] = s.SessionToken
+	}
+
+	out := ""
+	for _, k := range []string{"AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY", "AWS_SESSION_TOKEN"} {
+		v, ok := vars[k]
+		if !ok {
+			continue
+		}
+		out += s.formatLine(k, v)
+	}
+	return out
+}
+
+// formatLine renders a single KEY=value assignment in the target shell's
+// syntax.
+func (s *ShellCredential) formatLine(key, value string) string {
+	switch s.Shell {
+	case PowerShell:
+		return fmt.Sprintf("$env:%s = %s\n", key, powerShellSingleQuote(value))
+	case Cmd:
+		return fmt.Sprintf("set %s=%s\n", key, value)
+	case Fish:
+		return fmt.Sprintf("set -x %s %s\n", key, fishSingleQuote(value))
+	default: // Bash, Zsh
+		return fmt.Sprintf("export %s=%s\n", key, posixSingleQuote(value))
+	}
+}
+
+// posixSingleQuote wraps value in single quotes per POSIX sh/bash/zsh
+// rules, where a literal quote can't appear inside a single-quoted string
+// at all: it closes the quote, emits an escaped quote, then reopens it.
+func posixSingleQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// fishSingleQuote wraps value in single quotes per fish rules, where
+// (unlike POSIX) a backslash-escaped quote stays inside the quoted string
+// rather than closing it; applying the POSIX close/escape/reopen trick here
+// would instead leave the string unterminated.
+func fishSingleQuote(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, "'", `\'`)
+	return "'" + value + "'"
+}
+
+// powerShellSingleQuote wraps value in PowerShell's single-quoted literal
+// string syntax, which (unlike the double-quoted form) performs no
+// variable, backtick, or sub-expression interpolation. A literal quote is
+// represented by doubling it.
+func powerShellSingleQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// ParseShell maps a --shell flag value to a Shell. Defaults to Bash when
+// name is empty so the flag remains optional for POSIX users.
+func ParseShell(name string) (Shell, error) {
+	switch name {
+	case "", "bash":
+		return Bash, nil
+	case "zsh":
+		return Zsh, nil
+	case "fish":
+		return Fish, nil
+	case "powershell", "pwsh":
+		return PowerShell, nil
+	case "cmd":
+		return Cmd, nil
+	default:
+		return Bash, fmt.Errorf("unsupported shell %q", name)
+	}
+}
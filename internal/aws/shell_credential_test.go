@@ -0,0 +1,83 @@
+/*
+ * Copyright (c) 2022-Present, Okta, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package aws
+
+import "testing"
+
+func TestShellCredentialFormatLine(t *testing.T) {
+	tests := []struct {
+		name  string
+		shell Shell
+		value string
+		want  string
+	}{
+		{"bash plain", Bash, "plainvalue", "export KEY='plainvalue'\n"},
+		{"bash single quote", Bash, "it's", `export KEY='it'\''s'` + "\n"},
+		{"bash backslash", Bash, `back\slash`, `export KEY='back\slash'` + "\n"},
+		{"bash dollar and backtick", Bash, "$(rm -rf) `x`", "export KEY='$(rm -rf) `x`'\n"},
+		{"bash double quote", Bash, `say "hi"`, `export KEY='say "hi"'` + "\n"},
+
+		{"fish plain", Fish, "plainvalue", "set -x KEY 'plainvalue'\n"},
+		{"fish single quote", Fish, "it's", `set -x KEY 'it\'s'` + "\n"},
+		{"fish backslash", Fish, `back\slash`, `set -x KEY 'back\\slash'` + "\n"},
+		{"fish dollar and backtick", Fish, "$(rm -rf) `x`", "set -x KEY '$(rm -rf) `x`'\n"},
+		{"fish double quote", Fish, `say "hi"`, `set -x KEY 'say "hi"'` + "\n"},
+
+		{"powershell plain", PowerShell, "plainvalue", "$env:KEY = 'plainvalue'\n"},
+		{"powershell single quote", PowerShell, "it's", "$env:KEY = 'it''s'\n"},
+		{"powershell backslash", PowerShell, `back\slash`, "$env:KEY = 'back\\slash'\n"},
+		{"powershell dollar and backtick", PowerShell, "$env:EVIL `n", "$env:KEY = '$env:EVIL `n'\n"},
+		{"powershell double quote", PowerShell, `say "hi"`, "$env:KEY = 'say \"hi\"'\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &ShellCredential{Shell: tt.shell}
+			got := s.formatLine("KEY", tt.value)
+			if got != tt.want {
+				t.Errorf("formatLine(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseShell(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Shell
+		wantErr bool
+	}{
+		{"", Bash, false},
+		{"bash", Bash, false},
+		{"zsh", Zsh, false},
+		{"fish", Fish, false},
+		{"powershell", PowerShell, false},
+		{"pwsh", PowerShell, false},
+		{"cmd", Cmd, false},
+		{"tcsh", Bash, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseShell(tt.name)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseShell(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("ParseShell(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+/*
+ * Copyright (c) 2022-Present, Okta, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package aws
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// SSOCacheCredential representation of an AWS credential written to
+// ~/.aws/sso/cache/<sha1(startUrl)>.json using the same schema the AWS CLI
+// v2 uses for `sso_session` profiles, so federated-via-Okta credentials can
+// be picked up transparently by awscli/boto3.
+type SSOCacheCredential struct {
+	AccessToken string
+	ExpiresAt   time.Time
+	Region      string
+	StartURL    string
+
+	// CacheDir is the directory cache entries are written under, typically
+	// ~/.aws/sso/cache. Exposed for testing; callers should leave it unset
+	// in production.
+	CacheDir string
+}
+
+// IsCredential sso cache credential is a credential
+func (s *SSOCacheCredential) IsCredential() bool { return true }
+
+// ssoCacheEntry is the on-disk JSON shape expected by the AWS CLI v2 for an
+// sso_session cache file.
+type ssoCacheEntry struct {
+	AccessToken string `json:"accessToken"`
+	ExpiresAt   string `json:"expiresAt"`
+	Region      string `json:"region"`
+	StartURL    string `json:"startUrl"`
+}
+
+// Write renders s to its cache file, atomically replacing any existing
+// entry so concurrent SDK readers never observe a torn file.
+func (s *SSOCacheCredential) Write() error {
+	dir := s.CacheDir
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return fmt.Errorf("resolving home directory: %w", err)
+		}
+		dir = filepath.Join(home, ".aws", "sso", "cache")
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("creating sso cache dir: %w", err)
+	}
+
+	entry := ssoCacheEntry{
+		AccessToken: s.AccessToken,
+		// AWS CLI v2 expects the ISO8601 "Z" suffix, not "+00:00"; the time
+		// is converted to UTC and formatted with a literal "Z" at whole-second
+		// precision.
+		ExpiresAt: s.ExpiresAt.UTC().Format("2006-01-02T15:04:05Z"),
+		Region:    s.Region,
+		StartURL:  s.StartURL,
+	}
+
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("marshaling sso cache entry: %w", err)
+	}
+
+	path := filepath.Join(dir, s.cacheFileName())
+
+	tmp, err := os.CreateTemp(dir, ".sso-cache-*.tmp")
+	if err != nil {
+		return fmt.Errorf("creating sso cache tempfile: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing sso cache tempfile: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("closing sso cache tempfile: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0600); err != nil {
+		return fmt.Errorf("setting sso cache permissions: %w", err)
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// cacheFileName mirrors the AWS CLI's convention of naming the cache file
+// after the hex SHA1 of the SSO start URL.
+func (s *SSOCacheCredential) cacheFileName() string {
+	h := sha1.Sum([]byte(s.StartURL))
+	return hex.EncodeToString(h[:]) + ".json"
+}